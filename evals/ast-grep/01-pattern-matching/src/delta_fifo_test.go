@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSubscribeStopsOnIdleCancel guards against a goroutine leak where
+// Subscribe's loop only noticed ctx cancellation inside the handler
+// passed to Pop. If the queue was empty when ctx was canceled, Pop was
+// parked in cond.Wait() and nothing ever woke it, leaking the consumer
+// goroutine forever.
+func TestSubscribeStopsOnIdleCancel(t *testing.T) {
+	svc := NewService(&inMemoryRepo{data: make(map[string]string)})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	out := svc.Subscribe(ctx)
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatalf("received unexpected deltas on an idle, canceled subscription")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Subscribe did not close its channel after ctx was canceled while idle")
+	}
+}
+
+func TestDeltaFIFOPopReturnsErrShutdownAfterClose(t *testing.T) {
+	f := NewDeltaFIFO()
+	f.Close()
+
+	if err := f.Pop(func(Deltas) error { return nil }); !errors.Is(err, ErrShutdown) {
+		t.Fatalf("Pop() on a closed, empty FIFO = %v, want ErrShutdown", err)
+	}
+}
+
+// TestRequeueLockedMovesKeyToFront exercises the requeue-on-error path
+// under a concurrent enqueue: while the handler for "key" is running and
+// about to fail, another goroutine adds a fresh delta for "key", which
+// re-enqueues it at the back of the queue. Once the handler's error
+// triggers a requeue, key must end up at the front exactly once, per
+// Pop's documented "pushed back onto the front of the queue" behavior.
+func TestRequeueLockedMovesKeyToFront(t *testing.T) {
+	f := NewDeltaFIFO()
+	f.Add("other", "v0")
+	f.Add("key", "v1")
+
+	release := make(chan struct{})
+	failNext := true
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f.Pop(func(d Deltas) error {
+			if failNext {
+				<-release
+				return errors.New("boom")
+			}
+			return nil
+		})
+	}()
+
+	// Give Pop a chance to have dequeued "other" and be blocked handling
+	// it; meanwhile concurrently push a new delta for "other" so it's
+	// pending again when the requeue for "key" happens below.
+	time.Sleep(50 * time.Millisecond)
+	f.Add("other", "v2")
+	close(release)
+
+	if err := <-errCh; err == nil {
+		t.Fatalf("Pop() = nil, want a wrapped handler error")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.queue) == 0 || f.queue[0] != "other" {
+		t.Fatalf("queue = %v, want \"other\" at the front after its requeue", f.queue)
+	}
+	count := 0
+	for _, k := range f.queue {
+		if k == "other" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("queue = %v, want \"other\" to appear exactly once", f.queue)
+	}
+}
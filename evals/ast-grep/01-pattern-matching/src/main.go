@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"sync"
+
+	"github.com/stuckinforloop/harness/evals/ast-grep/01-pattern-matching/repository"
 )
 
 // Sentinel errors using errors.New
@@ -11,6 +15,7 @@ var (
 	ErrNotFound     = errors.New("resource not found")
 	ErrUnauthorized = errors.New("unauthorized access")
 	ErrConflict     = errors.New("resource already exists")
+	ErrShutdown     = errors.New("delta fifo is shutting down")
 )
 
 // ValidationError provides structured error context.
@@ -32,15 +37,180 @@ func (e *ValidationError) Unwrap() error {
 type Repository interface {
 	FindByID(id string) (string, error)
 	Save(id, value string) error
+	Update(id, value string) error
+	Delete(id string) error
+}
+
+// DeltaType describes the kind of change a Delta records.
+type DeltaType string
+
+const (
+	Added   DeltaType = "Added"
+	Updated DeltaType = "Updated"
+	Deleted DeltaType = "Deleted"
+	Sync    DeltaType = "Sync"
+)
+
+// Delta is a single recorded change to a keyed object.
+type Delta struct {
+	Type   DeltaType
+	Object string
+}
+
+// Deltas is an ordered list of changes recorded for one key, oldest first.
+type Deltas []Delta
+
+// DeltaFIFO records ordered change events per key and lets a single
+// consumer drain them in insertion order via Pop, modeled on client-go's
+// delta_fifo. A key's Deltas are coalesced as new events arrive, so a
+// consumer that falls behind still only ever sees the latest state.
+type DeltaFIFO struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  map[string]Deltas
+	queue  []string
+	closed bool
+}
+
+// NewDeltaFIFO creates an empty, ready-to-use DeltaFIFO.
+func NewDeltaFIFO() *DeltaFIFO {
+	f := &DeltaFIFO{items: make(map[string]Deltas)}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Add records that key was created with value.
+func (f *DeltaFIFO) Add(key, value string) {
+	f.enqueue(key, Delta{Type: Added, Object: value})
+}
+
+// Update records that key now has value.
+func (f *DeltaFIFO) Update(key, value string) {
+	f.enqueue(key, Delta{Type: Updated, Object: value})
+}
+
+// Delete records that key was removed.
+func (f *DeltaFIFO) Delete(key string) {
+	f.enqueue(key, Delta{Type: Deleted})
+}
+
+// Replace resets the queue to reflect list, as during a resync. Keys
+// present in list get a synthetic Sync delta; keys the FIFO still has
+// pending deltas for but that are missing from list get a Deleted delta.
+// resourceVersion is accepted for parity with the upstream Replace but
+// is not otherwise interpreted here.
+func (f *DeltaFIFO) Replace(list map[string]string, resourceVersion string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, value := range list {
+		f.enqueueLocked(key, Delta{Type: Sync, Object: value})
+	}
+	for key := range f.items {
+		if _, ok := list[key]; !ok {
+			f.enqueueLocked(key, Delta{Type: Deleted})
+		}
+	}
+}
+
+// Pop blocks until a key has pending deltas, removes it from the queue,
+// and invokes handler with its Deltas in insertion order. If handler
+// returns an error, the deltas are pushed back onto the front of the
+// queue so a later Pop replays them. Pop returns ErrShutdown once Close
+// has been called and no deltas remain.
+func (f *DeltaFIFO) Pop(handler func(Deltas) error) error {
+	f.mu.Lock()
+	for len(f.queue) == 0 {
+		if f.closed {
+			f.mu.Unlock()
+			return ErrShutdown
+		}
+		f.cond.Wait()
+	}
+	key := f.queue[0]
+	f.queue = f.queue[1:]
+	deltas := f.items[key]
+	delete(f.items, key)
+	f.mu.Unlock()
+
+	if err := handler(deltas); err != nil {
+		f.mu.Lock()
+		f.requeueLocked(key, deltas)
+		f.mu.Unlock()
+		return fmt.Errorf("pop %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close unblocks any Pop waiting for work and causes future Pop calls on
+// an empty queue to return ErrShutdown.
+func (f *DeltaFIFO) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	f.cond.Broadcast()
+}
+
+func (f *DeltaFIFO) enqueue(key string, d Delta) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.enqueueLocked(key, d)
+	f.cond.Signal()
+}
+
+func (f *DeltaFIFO) enqueueLocked(key string, d Delta) {
+	existing, ok := f.items[key]
+	if !ok {
+		f.queue = append(f.queue, key)
+	}
+	f.items[key] = coalesce(existing, d)
+}
+
+// requeueLocked pushes key back to the front of the queue, merging any
+// deltas recorded for it while it was being handled in front of deltas
+// that were just replayed. If a concurrent enqueue already re-added key
+// further back in the queue, that stale position is removed so key only
+// ever appears once, at the front.
+func (f *DeltaFIFO) requeueLocked(key string, deltas Deltas) {
+	if pending, ok := f.items[key]; ok {
+		deltas = append(deltas, pending...)
+		f.removeFromQueueLocked(key)
+	}
+	f.items[key] = deltas
+	f.queue = append([]string{key}, f.queue...)
+	f.cond.Signal()
+}
+
+// removeFromQueueLocked deletes key's existing entry from the queue, if
+// present, so callers can reinsert it at a new position without leaving
+// a duplicate behind.
+func (f *DeltaFIFO) removeFromQueueLocked(key string) {
+	for i, k := range f.queue {
+		if k == key {
+			f.queue = append(f.queue[:i], f.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// coalesce appends d to existing, collapsing an immediately preceding
+// Added with a new Updated into a single Added carrying the latest value.
+func coalesce(existing Deltas, d Delta) Deltas {
+	if n := len(existing); n > 0 && existing[n-1].Type == Added && d.Type == Updated {
+		existing[n-1].Object = d.Object
+		return existing
+	}
+	return append(existing, d)
 }
 
 // Service wraps a Repository with business logic.
 type Service struct {
 	repo Repository
+	fifo *DeltaFIFO
 }
 
 func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+	return &Service{repo: repo, fifo: NewDeltaFIFO()}
 }
 
 func (s *Service) Get(id string) (string, error) {
@@ -58,9 +228,59 @@ func (s *Service) Create(id, value string) error {
 	if err := s.repo.Save(id, value); err != nil {
 		return fmt.Errorf("create %s: %w", id, err)
 	}
+	s.fifo.Add(id, value)
 	return nil
 }
 
+// Update overwrites the value stored for id.
+func (s *Service) Update(id, value string) error {
+	if err := s.repo.Update(id, value); err != nil {
+		return fmt.Errorf("update %s: %w", id, err)
+	}
+	s.fifo.Update(id, value)
+	return nil
+}
+
+// Delete removes id from the repository.
+func (s *Service) Delete(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("delete %s: %w", id, err)
+	}
+	s.fifo.Delete(id)
+	return nil
+}
+
+// Subscribe drains the Service's delta queue on a background goroutine
+// and streams batches of Deltas until ctx is canceled, at which point
+// the returned channel is closed.
+func (s *Service) Subscribe(ctx context.Context) <-chan Deltas {
+	out := make(chan Deltas)
+	go func() {
+		<-ctx.Done()
+		s.fifo.Close()
+	}()
+	go func() {
+		defer close(out)
+		for {
+			err := s.fifo.Pop(func(d Deltas) error {
+				select {
+				case out <- d:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			if err != nil {
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
 // inMemoryRepo is a simple in-memory implementation.
 type inMemoryRepo struct {
 	data map[string]string
@@ -82,10 +302,29 @@ func (r *inMemoryRepo) Save(id, value string) error {
 	return nil
 }
 
+func (r *inMemoryRepo) Update(id, value string) error {
+	if _, exists := r.data[id]; !exists {
+		return ErrNotFound
+	}
+	r.data[id] = value
+	return nil
+}
+
+func (r *inMemoryRepo) Delete(id string) error {
+	if _, exists := r.data[id]; !exists {
+		return ErrNotFound
+	}
+	delete(r.data, id)
+	return nil
+}
+
 func main() {
 	repo := &inMemoryRepo{data: make(map[string]string)}
 	svc := NewService(repo)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	deltas := svc.Subscribe(ctx)
+
 	if err := svc.Create("user-1", "alice"); err != nil {
 		fmt.Fprintf(os.Stderr, "create failed: %v\n", err)
 		os.Exit(1)
@@ -112,4 +351,41 @@ func main() {
 			fmt.Println("correctly identified as not found")
 		}
 	}
+
+	fmt.Println("delta:", <-deltas)
+	cancel()
+
+	// Demonstrate that the pluggable backends in the repository package
+	// satisfy this package's Repository contract too: both a Service
+	// built from inMemoryRepo and one built via repository.Open behave
+	// the same way, including error parity on a missing key.
+	fsDir, err := os.MkdirTemp("", "harness-fsrepo-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(fsDir)
+
+	backends := map[string]string{
+		"mem": "mem://",
+		"fs":  "fs://" + fsDir,
+	}
+	for _, name := range []string{"mem", "fs"} {
+		backendRepo, err := repository.Open(backends[name])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "open %s backend: %v\n", name, err)
+			os.Exit(1)
+		}
+
+		backendSvc := NewService(backendRepo)
+		if err := backendSvc.Create("user-1", "alice"); err != nil {
+			fmt.Fprintf(os.Stderr, "%s backend: create failed: %v\n", name, err)
+			os.Exit(1)
+		}
+		if _, err := backendSvc.Get("missing"); !errors.Is(err, repository.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "%s backend: expected ErrNotFound, got %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s backend: create+lookup parity confirmed\n", name)
+	}
 }
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FSRepo is a filesystem-backed Repository that stores one file per key
+// under a base directory. Save writes to a temp file and renames it into
+// place so readers never observe a partial write. Ids must be flat file
+// names: they may not contain a path separator, since FSRepo never
+// creates subdirectories to hold them.
+type FSRepo struct {
+	dir string
+}
+
+// NewFSRepo returns a FSRepo rooted at dir, creating it if necessary.
+func NewFSRepo(dir string) (*FSRepo, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("new fs repo %s: %w", dir, err)
+	}
+	return &FSRepo{dir: dir}, nil
+}
+
+// path resolves id to a file directly under r.dir, rejecting any id that
+// contains a path separator (FSRepo stores flat files, not a directory
+// tree, so such an id would otherwise either escape r.dir or resolve
+// into a subdirectory that writeAtomic never creates) or that would
+// otherwise resolve outside of r.dir (e.g. an absolute path).
+func (r *FSRepo) path(id string) (string, error) {
+	if strings.ContainsAny(id, "/\\") {
+		return "", fmt.Errorf("invalid id %q: must not contain a path separator", id)
+	}
+	p := filepath.Join(r.dir, id)
+	rel, err := filepath.Rel(r.dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid id %q: escapes repository directory", id)
+	}
+	return p, nil
+}
+
+func (r *FSRepo) FindByID(id string) (string, error) {
+	p, err := r.path(id)
+	if err != nil {
+		return "", fmt.Errorf("find %s: %w", id, err)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("find %s: %w", id, ErrNotFound)
+		}
+		return "", fmt.Errorf("find %s: %w", id, err)
+	}
+	return string(data), nil
+}
+
+func (r *FSRepo) Save(id, value string) error {
+	p, err := r.path(id)
+	if err != nil {
+		return fmt.Errorf("save %s: %w", id, err)
+	}
+	if _, err := os.Stat(p); err == nil {
+		return fmt.Errorf("save %s: %w", id, ErrConflict)
+	}
+	return r.writeAtomic(p, value)
+}
+
+func (r *FSRepo) Update(id, value string) error {
+	p, err := r.path(id)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", id, err)
+	}
+	if _, err := os.Stat(p); os.IsNotExist(err) {
+		return fmt.Errorf("update %s: %w", id, ErrNotFound)
+	}
+	return r.writeAtomic(p, value)
+}
+
+func (r *FSRepo) Delete(id string) error {
+	p, err := r.path(id)
+	if err != nil {
+		return fmt.Errorf("delete %s: %w", id, err)
+	}
+	if err := os.Remove(p); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("delete %s: %w", id, ErrNotFound)
+		}
+		return fmt.Errorf("delete %s: %w", id, err)
+	}
+	return nil
+}
+
+// writeAtomic writes value to a temp file in r.dir and renames it onto p.
+func (r *FSRepo) writeAtomic(p, value string) error {
+	tmp, err := os.CreateTemp(r.dir, filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("save %s: %w", p, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(value); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save %s: %w", p, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save %s: %w", p, err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("save %s: %w", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import "fmt"
+
+// RedisClient is the small slice of Redis commands RedisRepo needs. It
+// exists so tests can substitute an in-memory fake instead of dialing a
+// real server.
+type RedisClient interface {
+	HSet(key, field, value string) error
+	HGet(key, field string) (string, error)
+	HExists(key, field string) (bool, error)
+	HDel(key, field string) error
+}
+
+// RedisDialer creates a RedisClient for a given "host:port" address and
+// database index. The package has no vendored Redis driver, so callers
+// that want redis:// DSNs to resolve via Open must set this to a dialer
+// backed by their driver of choice before calling Open.
+var RedisDialer func(addr string, db int) (RedisClient, error)
+
+// RedisRepo is a Redis-backed Repository storing all entries as fields
+// of a single hash key via HSET/HGET.
+type RedisRepo struct {
+	client RedisClient
+	hash   string
+}
+
+// NewRedisRepo dials addr/db via RedisDialer and returns a RedisRepo
+// backed by the "harness" hash key.
+func NewRedisRepo(addr string, db int) (*RedisRepo, error) {
+	if RedisDialer == nil {
+		return nil, fmt.Errorf("new redis repo %s/%d: no RedisDialer configured", addr, db)
+	}
+	client, err := RedisDialer(addr, db)
+	if err != nil {
+		return nil, fmt.Errorf("new redis repo %s/%d: %w", addr, db, err)
+	}
+	return NewRedisRepoWithClient(client), nil
+}
+
+// NewRedisRepoWithClient returns a RedisRepo backed by an already
+// constructed client, useful for tests that supply a fake.
+func NewRedisRepoWithClient(client RedisClient) *RedisRepo {
+	return &RedisRepo{client: client, hash: "harness"}
+}
+
+func (r *RedisRepo) FindByID(id string) (string, error) {
+	exists, err := r.client.HExists(r.hash, id)
+	if err != nil {
+		return "", fmt.Errorf("find %s: %w", id, err)
+	}
+	if !exists {
+		return "", fmt.Errorf("find %s: %w", id, ErrNotFound)
+	}
+	val, err := r.client.HGet(r.hash, id)
+	if err != nil {
+		return "", fmt.Errorf("find %s: %w", id, err)
+	}
+	return val, nil
+}
+
+func (r *RedisRepo) Save(id, value string) error {
+	exists, err := r.client.HExists(r.hash, id)
+	if err != nil {
+		return fmt.Errorf("save %s: %w", id, err)
+	}
+	if exists {
+		return fmt.Errorf("save %s: %w", id, ErrConflict)
+	}
+	if err := r.client.HSet(r.hash, id, value); err != nil {
+		return fmt.Errorf("save %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *RedisRepo) Update(id, value string) error {
+	exists, err := r.client.HExists(r.hash, id)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", id, err)
+	}
+	if !exists {
+		return fmt.Errorf("update %s: %w", id, ErrNotFound)
+	}
+	if err := r.client.HSet(r.hash, id, value); err != nil {
+		return fmt.Errorf("update %s: %w", id, err)
+	}
+	return nil
+}
+
+func (r *RedisRepo) Delete(id string) error {
+	exists, err := r.client.HExists(r.hash, id)
+	if err != nil {
+		return fmt.Errorf("delete %s: %w", id, err)
+	}
+	if !exists {
+		return fmt.Errorf("delete %s: %w", id, ErrNotFound)
+	}
+	if err := r.client.HDel(r.hash, id); err != nil {
+		return fmt.Errorf("delete %s: %w", id, err)
+	}
+	return nil
+}
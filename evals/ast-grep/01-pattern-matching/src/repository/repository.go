@@ -0,0 +1,104 @@
+// Package repository provides concrete Repository backends and a
+// DSN-based factory for selecting between them, mirroring the layered
+// DB abstraction used by projects like tendermint (FSDB alongside an
+// in-memory store).
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors shared by every backend. Backends must wrap these
+// with %w (not return unrelated errors) so errors.Is(err, ErrNotFound)
+// keeps working regardless of which backend is in use.
+var (
+	ErrNotFound = errors.New("resource not found")
+	ErrConflict = errors.New("resource already exists")
+)
+
+// Repository defines the data access interface every backend implements.
+type Repository interface {
+	FindByID(id string) (string, error)
+	Save(id, value string) error
+	Update(id, value string) error
+	Delete(id string) error
+}
+
+// Open constructs a Repository from a DSN. Supported schemes:
+//
+//	mem://                 in-memory, data lost on process exit
+//	fs:///var/lib/harness  one file per key under the given directory
+//	redis://host:6379/0    Redis-backed, HSET/HGET against one hash key
+func Open(dsn string) (Repository, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %q: %w", dsn, err)
+	}
+
+	switch u.Scheme {
+	case "mem":
+		return NewMemRepo(), nil
+	case "fs":
+		dir := u.Path
+		if dir == "" {
+			return nil, fmt.Errorf("open %q: fs scheme requires a path", dsn)
+		}
+		return NewFSRepo(dir)
+	case "redis":
+		db := 0
+		if p := strings.TrimPrefix(u.Path, "/"); p != "" {
+			db, err = strconv.Atoi(p)
+			if err != nil {
+				return nil, fmt.Errorf("open %q: invalid db %q: %w", dsn, p, err)
+			}
+		}
+		return NewRedisRepo(u.Host, db)
+	default:
+		return nil, fmt.Errorf("open %q: unsupported scheme %q", dsn, u.Scheme)
+	}
+}
+
+// MemRepo is the in-memory backend selected by the mem:// scheme.
+type MemRepo struct {
+	data map[string]string
+}
+
+func NewMemRepo() *MemRepo {
+	return &MemRepo{data: make(map[string]string)}
+}
+
+func (r *MemRepo) FindByID(id string) (string, error) {
+	val, ok := r.data[id]
+	if !ok {
+		return "", fmt.Errorf("find %s: %w", id, ErrNotFound)
+	}
+	return val, nil
+}
+
+func (r *MemRepo) Save(id, value string) error {
+	if _, exists := r.data[id]; exists {
+		return fmt.Errorf("save %s: %w", id, ErrConflict)
+	}
+	r.data[id] = value
+	return nil
+}
+
+func (r *MemRepo) Update(id, value string) error {
+	if _, exists := r.data[id]; !exists {
+		return fmt.Errorf("update %s: %w", id, ErrNotFound)
+	}
+	r.data[id] = value
+	return nil
+}
+
+func (r *MemRepo) Delete(id string) error {
+	if _, exists := r.data[id]; !exists {
+		return fmt.Errorf("delete %s: %w", id, ErrNotFound)
+	}
+	delete(r.data, id)
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRedisClient is an in-memory RedisClient used only by tests, so
+// RedisRepo's conformance can be checked without a real server.
+type fakeRedisClient struct {
+	mu   sync.Mutex
+	data map[string]map[string]string
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{data: make(map[string]map[string]string)}
+}
+
+func (c *fakeRedisClient) HSet(key, field, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.data[key]
+	if !ok {
+		h = make(map[string]string)
+		c.data[key] = h
+	}
+	h[field] = value
+	return nil
+}
+
+func (c *fakeRedisClient) HGet(key, field string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, ok := c.data[key][field]
+	if !ok {
+		return "", fmt.Errorf("no such field %s/%s", key, field)
+	}
+	return val, nil
+}
+
+func (c *fakeRedisClient) HExists(key, field string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.data[key][field]
+	return ok, nil
+}
+
+func (c *fakeRedisClient) HDel(key, field string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data[key], field)
+	return nil
+}
+
+// conformanceBackends returns a fresh instance of every Repository
+// implementation. Every backend added to this package must appear here.
+func conformanceBackends(t *testing.T) map[string]Repository {
+	t.Helper()
+
+	fsRepo, err := NewFSRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs repo: %v", err)
+	}
+
+	return map[string]Repository{
+		"mem":   NewMemRepo(),
+		"fs":    fsRepo,
+		"redis": NewRedisRepoWithClient(newFakeRedisClient()),
+	}
+}
+
+// TestRepositoryConformance exercises the Repository contract against
+// every backend so error wrapping stays consistent: errors.Is(err,
+// ErrNotFound) and errors.Is(err, ErrConflict) must hold no matter which
+// backend produced the error.
+func TestRepositoryConformance(t *testing.T) {
+	for name, repo := range conformanceBackends(t) {
+		name, repo := name, repo
+		t.Run(name, func(t *testing.T) {
+			if _, err := repo.FindByID("missing"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("FindByID(missing) = %v, want ErrNotFound", err)
+			}
+			if err := repo.Update("missing", "x"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Update(missing) = %v, want ErrNotFound", err)
+			}
+			if err := repo.Delete("missing"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Delete(missing) = %v, want ErrNotFound", err)
+			}
+
+			if err := repo.Save("id-1", "value-1"); err != nil {
+				t.Fatalf("Save(id-1): %v", err)
+			}
+			if err := repo.Save("id-1", "value-2"); !errors.Is(err, ErrConflict) {
+				t.Fatalf("Save(id-1) again = %v, want ErrConflict", err)
+			}
+
+			val, err := repo.FindByID("id-1")
+			if err != nil || val != "value-1" {
+				t.Fatalf("FindByID(id-1) = (%q, %v), want (%q, nil)", val, err, "value-1")
+			}
+
+			if err := repo.Update("id-1", "value-3"); err != nil {
+				t.Fatalf("Update(id-1): %v", err)
+			}
+			if val, err := repo.FindByID("id-1"); err != nil || val != "value-3" {
+				t.Fatalf("FindByID(id-1) after update = (%q, %v), want (%q, nil)", val, err, "value-3")
+			}
+
+			if err := repo.Delete("id-1"); err != nil {
+				t.Fatalf("Delete(id-1): %v", err)
+			}
+			if _, err := repo.FindByID("id-1"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("FindByID(id-1) after delete = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFSRepoRejectsPathTraversal(t *testing.T) {
+	repo, err := NewFSRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs repo: %v", err)
+	}
+
+	for _, id := range []string{"../escape", "a/../../escape", "/etc/passwd"} {
+		if _, err := repo.FindByID(id); err == nil {
+			t.Errorf("FindByID(%q) = nil error, want error", id)
+		}
+		if err := repo.Save(id, "value"); err == nil {
+			t.Errorf("Save(%q) = nil error, want error", id)
+		}
+		if err := repo.Delete(id); err == nil {
+			t.Errorf("Delete(%q) = nil error, want error", id)
+		}
+	}
+}
+
+// TestFSRepoRejectsNestedIDs guards against an id like "a/b" that stays
+// within r.dir (so it isn't caught by the path-traversal check) but
+// resolves into a subdirectory FSRepo never creates, which used to make
+// writeAtomic fail its rename with a confusing filesystem error instead
+// of a clean, documented one.
+func TestFSRepoRejectsNestedIDs(t *testing.T) {
+	repo, err := NewFSRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs repo: %v", err)
+	}
+
+	err = repo.Save("a/b", "value")
+	if err == nil {
+		t.Fatalf("Save(a/b) = nil error, want error")
+	}
+	if !strings.Contains(err.Error(), "path separator") {
+		t.Fatalf("Save(a/b) = %v, want a clean path-separator rejection, not a filesystem error", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("mem://"); err != nil {
+		t.Fatalf("Open(mem://): %v", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := Open("fs://" + dir); err != nil {
+		t.Fatalf("Open(fs://%s): %v", dir, err)
+	}
+
+	if _, err := Open("bogus://host"); err == nil {
+		t.Fatalf("Open(bogus://host) = nil error, want error")
+	}
+}
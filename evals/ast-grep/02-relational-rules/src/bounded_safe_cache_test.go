@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"0", 0, false},
+		{"64MB", 64 << 20, false},
+		{"1GB", 1 << 30, false},
+		{"512KB", 512 << 10, false},
+		{"2TB", 2 << 40, false},
+		{"10B", 10, false},
+		{"", 0, true},                     // empty
+		{"garbage", 0, true},              // unrecognized unit
+		{"10XB", 0, true},                 // garbage suffix
+		{"99999999999999999999", 0, true}, // out of int64 range, no unit to fall back on
+	}
+
+	for _, c := range cases {
+		got, err := ParseSize(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("ParseSize(%q) = %d, <nil>; want an error", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSize(%q) unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestBoundedSafeCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewBoundedSafeCache(10) // each key/value pair below costs 2 bytes
+
+	c.Set("a", "1")
+	c.Set("b", "2")
+	c.Set("c", "3")
+	c.Set("d", "4")
+	c.Set("e", "5") // total = 10, exactly at budget, no eviction yet
+
+	if got := c.Bytes(); got != 10 {
+		t.Fatalf("Bytes() = %d, want 10", got)
+	}
+	if got := c.Evictions(); got != 0 {
+		t.Fatalf("Evictions() = %d, want 0", got)
+	}
+
+	// Touch "a" so it is no longer the least recently used entry.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) = false, want true")
+	}
+
+	// Pushes the cache over budget; "b" is now the least recently used
+	// entry and should be the one evicted, not "a".
+	c.Set("f", "6")
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("Get(b) = true, want false (should have been evicted)")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) = false, want true (recently accessed, should survive)")
+	}
+	if got := c.Evictions(); got == 0 {
+		t.Fatalf("Evictions() = 0, want at least 1")
+	}
+	if got := c.Bytes(); got > 10 {
+		t.Fatalf("Bytes() = %d, want <= 10", got)
+	}
+}
+
+func TestBoundedSafeCacheConcurrentAccess(t *testing.T) {
+	c := NewBoundedSafeCache(1 << 16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			for j := 0; j < 100; j++ {
+				c.Set(key, fmt.Sprintf("value-%d-%d", i, j))
+				c.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
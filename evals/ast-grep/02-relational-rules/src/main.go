@@ -1,36 +1,100 @@
 package main
 
 import (
+	"container/list"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // ErrShutdown signals the service is shutting down.
 var ErrShutdown = errors.New("service is shutting down")
 
+// entry holds a cached value alongside its optional expiration time.
+// A zero expiresAt means the entry never expires.
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// Options configures a SafeCache created via NewSafeCacheWithOptions.
+type Options struct {
+	// DefaultTTL is applied by Set when no explicit TTL is given.
+	// Zero means entries never expire unless SetWithTTL is used.
+	DefaultTTL time.Duration
+	// SweepInterval controls how often the background janitor prunes
+	// expired entries. Zero disables the sweeper; expiry is then only
+	// enforced lazily on Get.
+	SweepInterval time.Duration
+}
+
 // SafeCache is a thread-safe cache with unexported mutex.
 type SafeCache struct {
-	mu    sync.RWMutex
-	items map[string]string
+	mu         sync.RWMutex
+	items      map[string]entry
+	defaultTTL time.Duration
+	done       chan struct{}
+	closed     bool
 }
 
 func NewSafeCache() *SafeCache {
-	return &SafeCache{items: make(map[string]string)}
+	return &SafeCache{items: make(map[string]entry)}
+}
+
+// NewSafeCacheWithOptions creates a SafeCache with a default TTL and,
+// if SweepInterval is non-zero, a background janitor goroutine that
+// periodically evicts expired entries.
+func NewSafeCacheWithOptions(opts Options) *SafeCache {
+	c := &SafeCache{
+		items:      make(map[string]entry),
+		defaultTTL: opts.DefaultTTL,
+		done:       make(chan struct{}),
+	}
+	if opts.SweepInterval > 0 {
+		go c.sweep(opts.SweepInterval)
+	}
+	return c
 }
 
 func (c *SafeCache) Get(key string) (string, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	val, ok := c.items[key]
-	return val, ok
+	e, ok := c.items[key]
+	if !ok || e.expired(time.Now()) {
+		return "", false
+	}
+	return e.value, true
 }
 
+// Set stores value under key using the cache's default TTL, if any.
 func (c *SafeCache) Set(key, value string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.items[key] = value
+	c.setLocked(key, value, c.defaultTTL)
+}
+
+// SetWithTTL stores value under key, overriding it to expire after ttl.
+// A zero ttl means the entry never expires.
+func (c *SafeCache) SetWithTTL(key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(key, value, ttl)
+}
+
+func (c *SafeCache) setLocked(key, value string, ttl time.Duration) {
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	c.items[key] = e
 }
 
 func (c *SafeCache) Delete(key string) {
@@ -39,6 +103,187 @@ func (c *SafeCache) Delete(key string) {
 	delete(c.items, key)
 }
 
+// sweep runs until Close is called, pruning expired entries every interval.
+func (c *SafeCache) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.pruneExpired()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *SafeCache) pruneExpired() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.items {
+		if e.expired(now) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// Close stops the background sweeper, if one is running, and is safe to
+// call on a cache created via NewSafeCache. It is idempotent: the first
+// call returns nil, and every call after that returns ErrShutdown.
+func (c *SafeCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrShutdown
+	}
+	c.closed = true
+	if c.done != nil {
+		close(c.done)
+	}
+	return nil
+}
+
+// boundedEntry is the payload stored in a BoundedSafeCache's list.List,
+// kept in least-recently-used order.
+type boundedEntry struct {
+	key   string
+	value string
+}
+
+func boundedEntrySize(key, value string) int64 {
+	return int64(len(key) + len(value))
+}
+
+// BoundedSafeCache is a thread-safe cache that evicts the least recently
+// used entry whenever storing a value would exceed a byte-size budget.
+// Recency is tracked with a container/list.List alongside a
+// map[string]*list.Element, the same pattern SafeCache uses for its
+// plain map, so Get and Set both touch the front of the list.
+type BoundedSafeCache struct {
+	mu        sync.Mutex
+	maxBytes  int64
+	curBytes  int64
+	ll        *list.List
+	items     map[string]*list.Element
+	evictions uint64
+}
+
+// NewBoundedSafeCache returns a BoundedSafeCache that evicts
+// least-recently-used entries once the stored keys and values together
+// exceed maxBytes.
+func NewBoundedSafeCache(maxBytes int64) *BoundedSafeCache {
+	return &BoundedSafeCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *BoundedSafeCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*boundedEntry).value, true
+}
+
+func (c *BoundedSafeCache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*boundedEntry)
+		c.curBytes += int64(len(value)) - int64(len(e.value))
+		e.value = value
+		c.ll.MoveToFront(el)
+	} else {
+		el := c.ll.PushFront(&boundedEntry{key: key, value: value})
+		c.items[key] = el
+		c.curBytes += boundedEntrySize(key, value)
+	}
+
+	for c.curBytes > c.maxBytes && c.ll.Len() > 0 {
+		c.evictOldestLocked()
+	}
+}
+
+func (c *BoundedSafeCache) evictOldestLocked() {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	c.ll.Remove(el)
+	e := el.Value.(*boundedEntry)
+	delete(c.items, e.key)
+	c.curBytes -= boundedEntrySize(e.key, e.value)
+	c.evictions++
+}
+
+// Len reports the number of entries currently stored.
+func (c *BoundedSafeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Bytes reports the current total size of all stored keys and values.
+func (c *BoundedSafeCache) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.curBytes
+}
+
+// Evictions reports how many entries have been evicted to stay within budget.
+func (c *BoundedSafeCache) Evictions() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
+
+// sizeUnits maps human-readable byte suffixes to their multiplier,
+// largest first so e.g. "KB" isn't matched inside "MB".
+var sizeUnits = []struct {
+	suffix string
+	mult   int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses a byte limit given either as a raw integer ("65536")
+// or a human-readable string with a unit suffix ("64MB"), mirroring the
+// edge blobstore's blobCacheSize configuration parsing.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("parse size: empty string")
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+
+	upper := strings.ToUpper(s)
+	for _, u := range sizeUnits {
+		if !strings.HasSuffix(upper, u.suffix) {
+			continue
+		}
+		numPart := strings.TrimSpace(s[:len(s)-len(u.suffix)])
+		n, err := strconv.ParseFloat(numPart, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse size %q: %w", s, err)
+		}
+		return int64(n * float64(u.mult)), nil
+	}
+	return 0, fmt.Errorf("parse size %q: unrecognized unit", s)
+}
+
 // processItem does work on a single item — library code, no panic or os.Exit.
 func processItem(cache *SafeCache, key, value string) error {
 	if key == "" {
@@ -57,7 +302,8 @@ func validateKey(key string) error {
 }
 
 func main() {
-	cache := NewSafeCache()
+	cache := NewSafeCacheWithOptions(Options{SweepInterval: time.Minute})
+	defer cache.Close()
 
 	keys := []string{"alpha", "beta", "gamma"}
 	for _, k := range keys {
@@ -78,4 +324,23 @@ func main() {
 		}
 		fmt.Printf("%s = %s\n", k, val)
 	}
+
+	cache.SetWithTTL("ephemeral", "value", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("ephemeral"); ok {
+		fmt.Fprintln(os.Stderr, "expected ephemeral key to have expired")
+		os.Exit(1)
+	}
+	fmt.Println("ephemeral key expired as expected")
+
+	limit, err := ParseSize("16B")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "parse size: %v\n", err)
+		os.Exit(1)
+	}
+	bounded := NewBoundedSafeCache(limit)
+	for _, k := range keys {
+		bounded.Set(k, "value-"+k)
+	}
+	fmt.Printf("bounded cache: len=%d bytes=%d evictions=%d\n", bounded.Len(), bounded.Bytes(), bounded.Evictions())
 }
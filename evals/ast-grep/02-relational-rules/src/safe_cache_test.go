@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSafeCacheLazyExpiry(t *testing.T) {
+	c := NewSafeCache()
+	c.SetWithTTL("key", "value", 10*time.Millisecond)
+
+	if _, ok := c.Get("key"); !ok {
+		t.Fatalf("Get(key) = false before ttl elapsed, want true")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Get("key"); ok {
+		t.Fatalf("Get(key) = true after ttl elapsed, want false (lazy expiry)")
+	}
+}
+
+func TestSafeCacheActiveSweepRemovesFromMap(t *testing.T) {
+	c := NewSafeCacheWithOptions(Options{SweepInterval: 5 * time.Millisecond})
+	defer c.Close()
+
+	c.SetWithTTL("key", "value", 5*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.RLock()
+		_, stillPresent := c.items["key"]
+		c.mu.RUnlock()
+		if !stillPresent {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expired entry was never swept from the underlying map")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSafeCacheCloseStopsSweeperGoroutine(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	c := NewSafeCacheWithOptions(Options{SweepInterval: time.Millisecond})
+	// Give the sweeper goroutine a chance to start before asserting it stops.
+	time.Sleep(10 * time.Millisecond)
+	c.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("sweeper goroutine leaked: NumGoroutine was %d before, still %d after Close", before, runtime.NumGoroutine())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSafeCacheCloseIsIdempotent(t *testing.T) {
+	c := NewSafeCacheWithOptions(Options{SweepInterval: time.Millisecond})
+	if err := c.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := c.Close(); !errors.Is(err, ErrShutdown) {
+		t.Fatalf("second Close() = %v, want ErrShutdown", err)
+	}
+}
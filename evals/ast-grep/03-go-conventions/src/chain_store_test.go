@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestChainStoreWriteBackPastChannelCapacity guards against a deadlock
+// where Write held cs.mu across the (possibly blocking) flushCh send
+// while flushLoop needed that same mutex to drain the channel: once the
+// buffer filled, the two goroutines waited on each other forever. The
+// flushCh buffer is 64 deep, so writing well past that must still
+// return promptly.
+func TestChainStoreWriteBackPastChannelCapacity(t *testing.T) {
+	l1, l2 := NewMapStore(), NewMapStore()
+	chain := NewChainStore(WriteBack, l1, l2)
+	defer chain.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 200; i++ {
+			if err := chain.Write("key", "value"); err != nil {
+				t.Errorf("Write: %v", err)
+				return
+			}
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Write past flushCh capacity did not return in time (deadlock?)")
+	}
+}
+
+// TestChainStoreWriteBackCloseDrains verifies Close waits for pending
+// write-back flushes to land in every layer before returning.
+func TestChainStoreWriteBackCloseDrains(t *testing.T) {
+	l1, l2 := NewMapStore(), NewMapStore()
+	chain := NewChainStore(WriteBack, l1, l2)
+
+	if err := chain.Write("key", "value"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := chain.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if val, err := l2.Read("key"); err != nil || val != "value" {
+		t.Fatalf("l2.Read(key) = (%q, %v), want (%q, nil) after Close drained the flush", val, err, "value")
+	}
+}
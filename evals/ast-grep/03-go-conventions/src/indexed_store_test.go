@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func byTagIndex(key, value string) []string {
+	return strings.Split(value, ",")
+}
+
+func TestIndexedStoreMultiValuedIndexer(t *testing.T) {
+	s := NewIndexedStore()
+	if err := s.AddIndex("byTag", byTagIndex); err != nil {
+		t.Fatalf("AddIndex: %v", err)
+	}
+
+	if err := s.Write("item-1", "red,blue"); err != nil {
+		t.Fatalf("Write(item-1): %v", err)
+	}
+	if err := s.Write("item-2", "blue,green"); err != nil {
+		t.Fatalf("Write(item-2): %v", err)
+	}
+
+	blues, err := s.ByIndex("byTag", "blue")
+	if err != nil {
+		t.Fatalf("ByIndex(byTag, blue): %v", err)
+	}
+	sort.Strings(blues)
+	if want := []string{"item-1", "item-2"}; !reflect.DeepEqual(blues, want) {
+		t.Fatalf("ByIndex(byTag, blue) = %v, want %v", blues, want)
+	}
+
+	reds, err := s.ByIndex("byTag", "red")
+	if err != nil {
+		t.Fatalf("ByIndex(byTag, red): %v", err)
+	}
+	if want := []string{"item-1"}; !reflect.DeepEqual(reds, want) {
+		t.Fatalf("ByIndex(byTag, red) = %v, want %v", reds, want)
+	}
+
+	// Overwriting item-1 without "red" should drop it from that bucket.
+	if err := s.Write("item-1", "blue"); err != nil {
+		t.Fatalf("Write(item-1) update: %v", err)
+	}
+	if reds, err := s.ByIndex("byTag", "red"); err != nil || len(reds) != 0 {
+		t.Fatalf("ByIndex(byTag, red) after update = (%v, %v), want (empty, nil)", reds, err)
+	}
+}
+
+func TestIndexedStoreAddIndexBackfills(t *testing.T) {
+	s := NewIndexedStore()
+	if err := s.Write("item-1", "a,b"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := s.AddIndex("byTag", byTagIndex); err != nil {
+		t.Fatalf("AddIndex: %v", err)
+	}
+
+	got, err := s.ByIndex("byTag", "a")
+	if err != nil {
+		t.Fatalf("ByIndex(byTag, a): %v", err)
+	}
+	if want := []string{"item-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ByIndex(byTag, a) = %v, want %v", got, want)
+	}
+}
+
+func TestIndexedStoreConcurrentWritesStayConsistent(t *testing.T) {
+	s := NewIndexedStore()
+	byLen := func(key, value string) []string {
+		return []string{fmt.Sprint(len(value))}
+	}
+	if err := s.AddIndex("byLen", byLen); err != nil {
+		t.Fatalf("AddIndex: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			for j := 1; j <= 20; j++ {
+				_ = s.Write(key, strings.Repeat("x", j%5+1))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key, value := range s.data {
+		wantIndexKey := fmt.Sprint(len(value))
+		set, ok := s.indices["byLen"][wantIndexKey]
+		if !ok {
+			t.Errorf("index bucket %q missing entirely for key %q", wantIndexKey, key)
+			continue
+		}
+		if _, ok := set[key]; !ok {
+			t.Errorf("key %q (value len %d) missing from its own index bucket %q", key, len(value), wantIndexKey)
+		}
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // Reader defines a minimal read interface.
@@ -20,9 +23,29 @@ type Deleter interface {
 	Delete(key string) error
 }
 
+// Store is the minimal contract a ChainStore layer must satisfy.
+type Store interface {
+	Reader
+	Writer
+}
+
+// Indexer adds named secondary indexes over a store's entries, modeled
+// on Kubernetes' thread_safe_store.
+type Indexer interface {
+	AddIndex(name string, indexFunc func(key, value string) []string) error
+	ByIndex(name, indexKey string) ([]string, error)
+}
+
 // Compile-time interface checks.
 var _ Reader = (*MapStore)(nil)
 var _ Writer = (*MapStore)(nil)
+var _ Reader = (*ChainStore)(nil)
+var _ Writer = (*ChainStore)(nil)
+var _ Deleter = (*ChainStore)(nil)
+var _ Reader = (*IndexedStore)(nil)
+var _ Writer = (*IndexedStore)(nil)
+var _ Deleter = (*IndexedStore)(nil)
+var _ Indexer = (*IndexedStore)(nil)
 
 // MapStore is a simple in-memory key-value store.
 type MapStore struct {
@@ -51,6 +74,313 @@ func (s *MapStore) Delete(key string) error {
 	return nil
 }
 
+// indexSet is the set of primary keys matching one index key.
+type indexSet map[string]struct{}
+
+// IndexedStore is a MapStore-like store that also maintains named
+// secondary indexes over its entries. Each index is derived from every
+// stored (key, value) pair by an indexFunc, which may return zero, one,
+// or several index keys per entry.
+type IndexedStore struct {
+	mu         sync.RWMutex
+	data       map[string]string
+	indexFuncs map[string]func(key, value string) []string
+	indices    map[string]map[string]indexSet // index name -> index key -> primary keys
+}
+
+// NewIndexedStore returns an empty, ready-to-use IndexedStore.
+func NewIndexedStore() *IndexedStore {
+	return &IndexedStore{
+		data:       make(map[string]string),
+		indexFuncs: make(map[string]func(key, value string) []string),
+		indices:    make(map[string]map[string]indexSet),
+	}
+}
+
+func (s *IndexedStore) Read(key string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok := s.data[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found", key)
+	}
+	return val, nil
+}
+
+func (s *IndexedStore) Write(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.data[key]; ok {
+		s.unindexLocked(key, old)
+	}
+	s.data[key] = value
+	s.indexLocked(key, value)
+	return nil
+}
+
+func (s *IndexedStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.data[key]
+	if !ok {
+		return nil
+	}
+	s.unindexLocked(key, old)
+	delete(s.data, key)
+	return nil
+}
+
+// AddIndex registers indexFunc under name and backfills it against every
+// entry already stored. It returns an error if name is already in use.
+func (s *IndexedStore) AddIndex(name string, indexFunc func(key, value string) []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.indexFuncs[name]; exists {
+		return fmt.Errorf("add index %q: already registered", name)
+	}
+
+	idx := make(map[string]indexSet)
+	for key, value := range s.data {
+		for _, indexKey := range indexFunc(key, value) {
+			addToIndexSet(idx, indexKey, key)
+		}
+	}
+	s.indexFuncs[name] = indexFunc
+	s.indices[name] = idx
+	return nil
+}
+
+// ByIndex returns every primary key whose indexed values include indexKey.
+func (s *IndexedStore) ByIndex(name, indexKey string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	idx, ok := s.indices[name]
+	if !ok {
+		return nil, fmt.Errorf("by index %q: not registered", name)
+	}
+	set := idx[indexKey]
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *IndexedStore) indexLocked(key, value string) {
+	for name, fn := range s.indexFuncs {
+		idx := s.indices[name]
+		for _, indexKey := range fn(key, value) {
+			addToIndexSet(idx, indexKey, key)
+		}
+	}
+}
+
+func (s *IndexedStore) unindexLocked(key, value string) {
+	for name, fn := range s.indexFuncs {
+		idx := s.indices[name]
+		for _, indexKey := range fn(key, value) {
+			set, ok := idx[indexKey]
+			if !ok {
+				continue
+			}
+			delete(set, key)
+			if len(set) == 0 {
+				delete(idx, indexKey)
+			}
+		}
+	}
+}
+
+func addToIndexSet(idx map[string]indexSet, indexKey, primaryKey string) {
+	set, ok := idx[indexKey]
+	if !ok {
+		set = make(indexSet)
+		idx[indexKey] = set
+	}
+	set[primaryKey] = struct{}{}
+}
+
+// WriteMode controls how ChainStore.Write propagates to its layers.
+type WriteMode int
+
+const (
+	// WriteThrough writes to every layer before returning.
+	WriteThrough WriteMode = iota
+	// WriteBack writes to the first (fastest) layer synchronously and
+	// flushes to the remaining layers asynchronously.
+	WriteBack
+)
+
+// layerStats tracks per-layer hit/miss counts.
+type layerStats struct {
+	hits   uint64
+	misses uint64
+}
+
+// Stats reports read hit/miss counts for a single ChainStore layer.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// multiError collects one error per failing layer.
+type multiError struct {
+	errs []error
+}
+
+func (m *multiError) Error() string {
+	parts := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		parts[i] = err.Error()
+	}
+	return "chain store: " + strings.Join(parts, "; ")
+}
+
+func (m *multiError) Unwrap() []error {
+	return m.errs
+}
+
+type writeOp struct {
+	key   string
+	value string
+}
+
+// ChainStore composes an ordered list of Stores into one Reader+Writer,
+// following the gocache chain pattern: L1 (fast) fronts L2 (durable),
+// and so on. Read walks the layers in order and, on a hit below the
+// front, populates every faster layer that missed (read-through). Write
+// either fans out to all layers (WriteThrough) or writes the front layer
+// synchronously and flushes the rest on a background goroutine
+// (WriteBack). Delete always invalidates every layer that supports it.
+type ChainStore struct {
+	mu      sync.Mutex
+	layers  []Store
+	mode    WriteMode
+	stats   []layerStats
+	flushCh chan writeOp
+	wg      sync.WaitGroup
+}
+
+// NewChainStore composes layers, front (fastest) first, into a ChainStore.
+func NewChainStore(mode WriteMode, layers ...Store) *ChainStore {
+	cs := &ChainStore{
+		layers: layers,
+		mode:   mode,
+		stats:  make([]layerStats, len(layers)),
+	}
+	if mode == WriteBack {
+		cs.flushCh = make(chan writeOp, 64)
+		cs.wg.Add(1)
+		go cs.flushLoop()
+	}
+	return cs
+}
+
+func (cs *ChainStore) Read(key string) (string, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for i, layer := range cs.layers {
+		val, err := layer.Read(key)
+		if err != nil {
+			cs.stats[i].misses++
+			continue
+		}
+		cs.stats[i].hits++
+		for j := 0; j < i; j++ {
+			_ = cs.layers[j].Write(key, val)
+		}
+		return val, nil
+	}
+	return "", fmt.Errorf("chain store: key %q not found in any layer", key)
+}
+
+func (cs *ChainStore) Write(key, value string) error {
+	if cs.mode == WriteBack {
+		// cs.mu guards layers[0] only here; it must be released before
+		// the flushCh send below, which can block when the buffer is
+		// full. Holding it across the send would deadlock against
+		// flushLoop, which needs cs.mu to write the drained item to the
+		// remaining layers.
+		cs.mu.Lock()
+		err := cs.layers[0].Write(key, value)
+		cs.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("chain store: write layer 0: %w", err)
+		}
+		cs.flushCh <- writeOp{key: key, value: value}
+		return nil
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	var errs []error
+	for i, layer := range cs.layers {
+		if err := layer.Write(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("layer %d: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &multiError{errs: errs}
+	}
+	return nil
+}
+
+func (cs *ChainStore) Delete(key string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	var errs []error
+	for i, layer := range cs.layers {
+		d, ok := layer.(Deleter)
+		if !ok {
+			continue
+		}
+		if err := d.Delete(key); err != nil {
+			errs = append(errs, fmt.Errorf("layer %d: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &multiError{errs: errs}
+	}
+	return nil
+}
+
+// Close stops the write-back flush goroutine, if one is running, after
+// draining any pending writes. It is a no-op for WriteThrough stores.
+func (cs *ChainStore) Close() error {
+	if cs.mode != WriteBack {
+		return nil
+	}
+	close(cs.flushCh)
+	cs.wg.Wait()
+	return nil
+}
+
+func (cs *ChainStore) flushLoop() {
+	defer cs.wg.Done()
+	for op := range cs.flushCh {
+		cs.mu.Lock()
+		for i := 1; i < len(cs.layers); i++ {
+			_ = cs.layers[i].Write(op.key, op.value)
+		}
+		cs.mu.Unlock()
+	}
+}
+
+// Stats reports per-layer read hit/miss counts, front layer first.
+func (cs *ChainStore) Stats() []Stats {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	out := make([]Stats, len(cs.stats))
+	for i, s := range cs.stats {
+		out[i] = Stats{Hits: s.hits, Misses: s.misses}
+	}
+	return out
+}
+
 func main() {
 	store := NewMapStore()
 
@@ -74,4 +404,58 @@ func main() {
 		}
 		fmt.Println("deleted greeting")
 	}
+
+	l1, l2 := NewMapStore(), NewMapStore()
+	chain := NewChainStore(WriteThrough, l1, l2)
+	defer chain.Close()
+
+	if err := chain.Write("user-1", "alice"); err != nil {
+		fmt.Fprintf(os.Stderr, "chain write failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Simulate an L1 eviction: the value still resolves from L2 and
+	// read-through repopulates L1.
+	l1.Delete("user-1")
+	if val, err := chain.Read("user-1"); err != nil {
+		fmt.Fprintf(os.Stderr, "chain read failed: %v\n", err)
+		os.Exit(1)
+	} else {
+		fmt.Println("chain read:", val)
+	}
+
+	if _, err := l1.Read("user-1"); err != nil {
+		fmt.Fprintf(os.Stderr, "expected read-through to repopulate L1: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("read-through repopulated L1")
+	fmt.Printf("chain stats: %+v\n", chain.Stats())
+
+	indexed := NewIndexedStore()
+	byRole := func(key, value string) []string {
+		role, _, _ := strings.Cut(value, ":")
+		return []string{role}
+	}
+	if err := indexed.AddIndex("byRole", byRole); err != nil {
+		fmt.Fprintf(os.Stderr, "add index failed: %v\n", err)
+		os.Exit(1)
+	}
+	for key, value := range map[string]string{
+		"user-1": "admin:alice",
+		"user-2": "admin:bob",
+		"user-3": "member:carol",
+	} {
+		if err := indexed.Write(key, value); err != nil {
+			fmt.Fprintf(os.Stderr, "indexed write failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	admins, err := indexed.ByIndex("byRole", "admin")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "by index failed: %v\n", err)
+		os.Exit(1)
+	}
+	sort.Strings(admins)
+	fmt.Println("admins:", admins)
 }